@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/callicoder/go-docker/internal/useragent"
+)
+
+// agentResponse is the JSON shape returned by userAgentHandler.
+type agentResponse struct {
+	Browser        string           `json:"browser"`
+	BrowserVersion string           `json:"browser_version"`
+	Engine         string           `json:"engine"`
+	EngineVersion  string           `json:"engine_version"`
+	OS             string           `json:"os"`
+	OSVersion      string           `json:"os_version"`
+	Device         useragent.Device `json:"device"`
+	Mobile         bool             `json:"mobile"`
+	Bot            bool             `json:"bot"`
+	BotName        string           `json:"bot_name,omitempty"`
+	UserAgent      string           `json:"user_agent"`
+	ClientIP       string           `json:"client_ip"`
+}
+
+// botClassification is the short-circuit response for /agent?classify=bot.
+type botClassification struct {
+	Bot  bool   `json:"bot"`
+	Name string `json:"name,omitempty"`
+}
+
+// userAgentHandler parses the request's User-Agent header via the useragent
+// package. /agent?classify=bot short-circuits to just the bot verdict.
+func userAgentHandler(w http.ResponseWriter, r *http.Request) {
+	result := useragent.Parse(r.UserAgent())
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	if r.URL.Query().Get("classify") == "bot" {
+		_ = json.NewEncoder(w).Encode(botClassification{
+			Bot:  result.Bot,
+			Name: result.BotName,
+		})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(agentResponse{
+		Browser:        result.BrowserName,
+		BrowserVersion: result.BrowserVersion,
+		Engine:         result.EngineName,
+		EngineVersion:  result.EngineVersion,
+		OS:             result.OSName,
+		OSVersion:      result.OSVersion,
+		Device:         result.Device,
+		Mobile:         result.Mobile,
+		Bot:            result.Bot,
+		BotName:        result.BotName,
+		UserAgent:      result.UserAgent,
+		ClientIP:       getClientIP(r),
+	})
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUserAgentHandler(t *testing.T) {
+	req := httptest.NewRequest("GET", "/agent", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.212 Safari/537.36")
+	req.RemoteAddr = "6.6.6.6:2222"
+	rr := httptest.NewRecorder()
+	userAgentHandler(rr, req)
+
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("unexpected content type: %s", ct)
+	}
+
+	var resp agentResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode json: %v", err)
+	}
+	if resp.Browser != "Chrome" {
+		t.Errorf("expected browser Chrome, got %s", resp.Browser)
+	}
+	if resp.OS != "Windows" {
+		t.Errorf("expected os Windows, got %s", resp.OS)
+	}
+	if resp.ClientIP != "6.6.6.6" {
+		t.Errorf("expected client_ip 6.6.6.6, got %s", resp.ClientIP)
+	}
+	if resp.UserAgent == "" {
+		t.Errorf("expected non-empty user_agent")
+	}
+}
+
+func TestUserAgentHandler_ClassifyBot(t *testing.T) {
+	req := httptest.NewRequest("GET", "/agent?classify=bot", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+	rr := httptest.NewRecorder()
+	userAgentHandler(rr, req)
+
+	var resp botClassification
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode json: %v", err)
+	}
+	if !resp.Bot {
+		t.Errorf("expected bot=true")
+	}
+	if resp.Name != "Googlebot" {
+		t.Errorf("expected name Googlebot, got %s", resp.Name)
+	}
+}
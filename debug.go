@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/callicoder/go-docker/internal/cache"
+)
+
+// healthzHandler reports whether the process is up. It never checks
+// downstream dependencies, so a load balancer can use it for liveness.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// readyzHandler reports whether the process is ready to serve traffic. Redis
+// is required for most routes, so readiness is gated on appCache responding.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if _, err := appCache.Get(r.Context(), "readyz-probe"); err != nil && !errors.Is(err, cache.ErrNotFound) {
+		http.Error(w, "cache unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// debugRequestsHandler dumps the last entries recorded in requestRing,
+// oldest first, for quick ops debugging without grepping the access log.
+func debugRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(requestRing.Entries())
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/callicoder/go-docker/internal/cache"
+	"github.com/callicoder/go-docker/internal/reqlog"
+)
+
+func TestHealthzHandler(t *testing.T) {
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	rr := httptest.NewRecorder()
+	healthzHandler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rr.Code)
+	}
+}
+
+func TestReadyzHandler(t *testing.T) {
+	appCache = cache.NewFakeCache()
+
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	rr := httptest.NewRecorder()
+	readyzHandler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rr.Code)
+	}
+}
+
+func TestDebugRequestsHandler(t *testing.T) {
+	ring := reqlog.NewRing(10)
+	ring.Add(reqlog.Entry{Path: "/info", Status: http.StatusOK})
+	old := requestRing
+	requestRing = ring
+	defer func() { requestRing = old }()
+
+	req := httptest.NewRequest("GET", "/debug/requests", nil)
+	rr := httptest.NewRecorder()
+	debugRequestsHandler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+	if got := rr.Body.String(); got == "" || got == "[]\n" {
+		t.Errorf("expected recorded entries in response, got %q", got)
+	}
+}
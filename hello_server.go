@@ -10,18 +10,24 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
-	"github.com/go-redis/redis/v8"
+	"github.com/callicoder/go-docker/internal/auth"
+	"github.com/callicoder/go-docker/internal/cache"
+	"github.com/callicoder/go-docker/internal/reqlog"
 	"github.com/gorilla/mux"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-// Redis client
-var rdb *redis.Client
+// appCache is the Redis-backed cache shared by the /redis, /redis/mget and
+// /pubsub handlers.
+var appCache cache.Cache
+
+// requestRing backs /debug/requests with the last 100 requests, including
+// ones excluded from the access log (see reqlog.Middleware).
+var requestRing = reqlog.NewRing(100)
 
 func handler(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
@@ -29,8 +35,6 @@ func handler(w http.ResponseWriter, r *http.Request) {
 	if name == "" {
 		name = "Guest"
 	}
-	log.Printf("Received request for %s\n", name)
-	log.Printf(" %v\n", r)
 	w.Write([]byte(fmt.Sprintf("Hello, %s\n", name)))
 }
 
@@ -47,17 +51,20 @@ func main() {
 		})
 	}
 
-	// Initialize Redis
-	initRedis()
-	seedRedisData(context.Background())
+	// Initialize Redis-backed cache
+	rc, err := cache.NewRedisCache(cache.LoadConfig())
+	if err != nil {
+		log.Fatalf("Could not connect to Redis: %v", err)
+	}
+	appCache = rc
+	seedCache(context.Background(), appCache)
 
 	// Create Server and Route Handlers
-	r := mux.NewRouter()
+	r := newRouter()
+	h := wireMiddleware(r)
 
-	r.HandleFunc("/", handler)
-	registerExtraRoutes(r)
 	srv := &http.Server{
-		Handler:      r,
+		Handler:      h,
 		Addr:         ":8080",
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
@@ -91,97 +98,57 @@ func waitForShutdown(srv *http.Server) {
 	os.Exit(0)
 }
 
+// newRouter builds the router and registers every route on it.
+func newRouter() *mux.Router {
+	r := mux.NewRouter()
+	r.HandleFunc("/", handler)
+	registerExtraRoutes(r)
+	return r
+}
+
+// wireMiddleware wraps r with the full middleware stack from the outside,
+// instead of installing it via r.Use(). gorilla/mux v1.6.2's Router.Match
+// only builds the r.Use() chain once a route has matched, so an
+// r.Use()-installed middleware never runs on a 404 or 405 — exactly the
+// traffic (scanners, typo'd paths, broken clients) reqlog.Middleware exists
+// to capture. Wrapping outside the router means every request, matched or
+// not, gets a request ID, a JSON log line, and ProxyHeaders/recovery/
+// CORS/compression.
+func wireMiddleware(r *mux.Router) http.Handler {
+	var h http.Handler = r
+	mws := buildMiddleware(loadMiddlewareConfig())
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	h = reqlog.Middleware(log.Writer(), requestRing)(h)
+	h = ProxyHeaders(loadTrustedProxies())(h)
+	return h
+}
+
 func registerExtraRoutes(r *mux.Router) {
 	r.HandleFunc("/info", infoHandler)
 	r.HandleFunc("/agent", userAgentHandler)
 	r.HandleFunc("/headers", headersHandler)
 	r.HandleFunc("/ip", clientIPHandler)
 	r.HandleFunc("/echo", echoHandler).Methods("GET", "POST")
-	r.HandleFunc("/redis", redisHandler).Methods("GET") // New Redis route
-}
-
-// --- New Redis Functions ---
-
-func initRedis() {
-	// Configuration from environment variables
-	addr := os.Getenv("REDIS_ADDR")
-	if addr == "" {
-		addr = "test-db-e2kuf-redis-master.test-db-e2kuf.svc.cluster.local:6379" // default
-	}
-	//password := os.Getenv("REDIS_PASSWORD") // no password by default
-	dbStr := os.Getenv("REDIS_DB")
-	if dbStr == "" {
-		dbStr = "0" // default db
-	}
-	db, err := strconv.Atoi(dbStr)
-	if err != nil {
-		log.Fatalf("Invalid Redis DB number: %v", err)
-	}
-
-	rdb = redis.NewClient(&redis.Options{
-		Addr:     "test-g6r4t-redis-master.test-g6r4t.svc.cluster.local:6379",
-		Password: "cE0+mF2_sV3_cQ3+vT0-",
-		DB:       db,
-	})
-
-	// Ping to check connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if _, err := rdb.Ping(ctx).Result(); err != nil {
-		log.Fatalf("Could not connect to Redis: %v", err)
-	}
-	log.Println("Connected to Redis successfully.")
-}
-
-// seedRedisData adds some predetermined data to Redis.
-func seedRedisData(ctx context.Context) {
-	log.Println("Seeding Redis with initial data...")
-	err := rdb.Set(ctx, "app:name", "go-hello-server", 0).Err()
-	if err != nil {
-		log.Printf("Failed to seed data 'app:name': %v", err)
-	}
-	err = rdb.Set(ctx, "user:1:name", "Cetriolo", 0).Err()
-	if err != nil {
-		log.Printf("Failed to seed data 'user:1:name': %v", err)
-	}
-}
-
-// redisHandler retrieves a value from Redis by key.
-// Example: /redis?key=app:name
-func redisHandler(w http.ResponseWriter, r *http.Request) {
-	key := r.URL.Query().Get("key")
-	if key == "" {
-		http.Error(w, "Query parameter 'key' is required", http.StatusBadRequest)
-		return
-	}
-
-	val, err := rdb.Get(r.Context(), key).Result()
-	if err == redis.Nil {
-		http.Error(w, fmt.Sprintf("Key '%s' not found", key), http.StatusNotFound)
-		return
-	} else if err != nil {
-		http.Error(w, "Failed to retrieve data from Redis", http.StatusInternalServerError)
-		log.Printf("Redis GET error for key '%s': %v", key, err)
-		return
-	}
-
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	fmt.Fprintln(w, val)
+	r.HandleFunc("/redis", redisHandler).Methods("GET")
+	r.HandleFunc("/redis/mget", mgetHandler).Methods("GET")
+	r.HandleFunc("/pubsub", pubsubHandler).Methods("GET")
+	r.HandleFunc("/healthz", healthzHandler).Methods("GET")
+	r.HandleFunc("/readyz", readyzHandler).Methods("GET")
+	r.HandleFunc("/debug/requests", debugRequestsHandler).Methods("GET")
+
+	requireJWT := auth.RequireJWT(auth.LoadConfig())
+	r.Handle("/whoami", requireJWT(http.HandlerFunc(whoamiHandler))).Methods("GET")
 }
 
 // --- Existing Handlers ---
 
+// getClientIP returns the client IP from r.RemoteAddr. Proxy headers
+// (X-Forwarded-For, X-Real-Ip, Forwarded) are no longer read here: the
+// ProxyHeaders middleware rewrites RemoteAddr itself, and only for peers in
+// TRUSTED_PROXIES.
 func getClientIP(r *http.Request) string {
-	// Check common proxy headers first
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		// X-Forwarded-For can contain multiple addresses, take the first
-		parts := strings.Split(xff, ",")
-		return strings.TrimSpace(parts[0])
-	}
-	if xr := r.Header.Get("X-Real-Ip"); xr != "" {
-		return xr
-	}
-	// Fallback to RemoteAddr (strip port)
 	host, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
 		return r.RemoteAddr
@@ -202,50 +169,6 @@ func infoHandler(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(info)
 }
 
-func userAgentHandler(w http.ResponseWriter, r *http.Request) {
-	ua := r.UserAgent()
-	browser := "Unknown"
-	switch {
-	case strings.Contains(ua, "OPR") || strings.Contains(ua, "Opera"):
-		browser = "Opera"
-	case strings.Contains(ua, "Edg") || strings.Contains(ua, "Edge"):
-		browser = "Edge"
-	case strings.Contains(ua, "Chrome") && !strings.Contains(ua, "Chromium"):
-		browser = "Chrome"
-	case strings.Contains(ua, "Chromium"):
-		browser = "Chromium"
-	case strings.Contains(ua, "Firefox"):
-		browser = "Firefox"
-	case strings.Contains(ua, "Safari") && !strings.Contains(ua, "Chrome"):
-		browser = "Safari"
-	case strings.Contains(ua, "MSIE") || strings.Contains(ua, "Trident"):
-		browser = "Internet Explorer"
-	}
-
-	osname := "Unknown"
-	switch {
-	case strings.Contains(ua, "Windows"):
-		osname = "Windows"
-	case strings.Contains(ua, "Macintosh"):
-		osname = "macOS"
-	case strings.Contains(ua, "Android"):
-		osname = "Android"
-	case strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPad"):
-		osname = "iOS"
-	case strings.Contains(ua, "Linux"):
-		osname = "Linux"
-	}
-
-	resp := map[string]string{
-		"browser":    browser,
-		"os":         osname,
-		"user_agent": ua,
-		"client_ip":  getClientIP(r),
-	}
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	_ = json.NewEncoder(w).Encode(resp)
-}
-
 func headersHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	for k, v := range r.Header {
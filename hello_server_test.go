@@ -3,8 +3,10 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 )
@@ -48,33 +50,18 @@ func TestHandler(t *testing.T) {
 }
 
 func TestGetClientIP(t *testing.T) {
-	// X-Forwarded-For should take precedence and first IP returned
+	// getClientIP only ever reads RemoteAddr; proxy headers are handled
+	// upstream by the ProxyHeaders middleware (see proxyheaders_test.go).
 	req := httptest.NewRequest("GET", "/", nil)
-	req.Header.Set("X-Forwarded-For", "1.2.3.4, 5.6.7.8")
-	req.RemoteAddr = "9.9.9.9:1234"
-	if ip := getClientIP(req); ip != "1.2.3.4" {
-		t.Errorf("expected 1.2.3.4, got %s", ip)
-	}
-
-	// X-Real-Ip used if X-Forwarded-For empty
-	req2 := httptest.NewRequest("GET", "/", nil)
-	req2.Header.Set("X-Real-Ip", "2.2.2.2")
-	req2.RemoteAddr = "9.9.9.9:1234"
-	if ip := getClientIP(req2); ip != "2.2.2.2" {
-		t.Errorf("expected 2.2.2.2, got %s", ip)
-	}
-
-	// fallback to RemoteAddr (without port)
-	req3 := httptest.NewRequest("GET", "/", nil)
-	req3.RemoteAddr = "3.3.3.3:5678"
-	if ip := getClientIP(req3); ip != "3.3.3.3" {
+	req.RemoteAddr = "3.3.3.3:5678"
+	if ip := getClientIP(req); ip != "3.3.3.3" {
 		t.Errorf("expected 3.3.3.3, got %s", ip)
 	}
 
 	// if RemoteAddr is not parseable, return as-is
-	req4 := httptest.NewRequest("GET", "/", nil)
-	req4.RemoteAddr = "bad-addr"
-	if ip := getClientIP(req4); ip != "bad-addr" {
+	req2 := httptest.NewRequest("GET", "/", nil)
+	req2.RemoteAddr = "bad-addr"
+	if ip := getClientIP(req2); ip != "bad-addr" {
 		t.Errorf("expected bad-addr, got %s", ip)
 	}
 }
@@ -147,6 +134,32 @@ func TestClientIPHandler(t *testing.T) {
 	}
 }
 
+func TestWireMiddleware_NotFoundIsLoggedAndGetsRequestID(t *testing.T) {
+	// gorilla/mux v1.6.2 only runs r.Use() middleware once a route has
+	// matched, so this drives a 404 through the fully assembled handler
+	// (the same one main() hands to http.Server) rather than calling a
+	// middleware function directly on a stub handler.
+	var logBuf strings.Builder
+	log.SetOutput(&logBuf)
+	defer log.SetOutput(os.Stderr)
+
+	h := wireMiddleware(newRouter())
+
+	req := httptest.NewRequest(http.MethodGet, "/this-path-does-not-exist", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	if rr.Header().Get("X-Request-ID") == "" {
+		t.Error("expected X-Request-ID to be set on a 404 response")
+	}
+	if !strings.Contains(logBuf.String(), `"status":404`) {
+		t.Errorf("expected a structured JSON log line for the 404, got %q", logBuf.String())
+	}
+}
+
 func TestInfoHandler(t *testing.T) {
 	req := httptest.NewRequest("GET", "/info", nil)
 	req.RemoteAddr = "5.5.5.5:1111"
@@ -175,32 +188,3 @@ func TestInfoHandler(t *testing.T) {
 		t.Errorf("expected path /info, got %s", info["path"])
 	}
 }
-
-func TestUserAgentHandler(t *testing.T) {
-	req := httptest.NewRequest("GET", "/agent", nil)
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/90.0")
-	req.RemoteAddr = "6.6.6.6:2222"
-	rr := httptest.NewRecorder()
-	userAgentHandler(rr, req)
-
-	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
-		t.Errorf("unexpected content type: %s", ct)
-	}
-
-	var resp map[string]string
-	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
-		t.Fatalf("failed to decode json: %v", err)
-	}
-	if resp["browser"] != "Chrome" {
-		t.Errorf("expected browser Chrome, got %s", resp["browser"])
-	}
-	if resp["os"] != "Windows" {
-		t.Errorf("expected os Windows, got %s", resp["os"])
-	}
-	if resp["client_ip"] != "6.6.6.6" {
-		t.Errorf("expected client_ip 6.6.6.6, got %s", resp["client_ip"])
-	}
-	if resp["user_agent"] == "" {
-		t.Errorf("expected non-empty user_agent")
-	}
-}
@@ -0,0 +1,94 @@
+// Package auth provides a JWT authentication middleware modeled on
+// Cloudflare Access: it accepts a bearer token from either the standard
+// Authorization header or the headers cloudflared injects
+// (Cf-Access-Jwt-Assertion / Cf-Access-Token), verifies it against a JWKS
+// endpoint, and exposes the validated claims to handlers via the request
+// context.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+// Claims are the JWT claims RequireJWT injects into the request context.
+type Claims struct {
+	jwt.RegisteredClaims
+	Email string `json:"email,omitempty"`
+}
+
+type contextKey struct{}
+
+var claimsContextKey contextKey
+
+// ClaimsFromContext returns the Claims a previous RequireJWT call validated,
+// if any.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey).(Claims)
+	return claims, ok
+}
+
+// RequireJWT returns middleware that rejects requests without a valid JWT.
+// It fetches cfg.JWKSURL once synchronously before returning so the first
+// request doesn't pay the fetch latency, then refreshes in the background
+// every cfg.JWKSTTL.
+func RequireJWT(cfg Config) mux.MiddlewareFunc {
+	ks := newKeySet(cfg.JWKSURL)
+	_ = ks.refresh(context.Background())
+	ks.watch(context.Background(), cfg.JWKSTTL)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenString := bearerToken(r)
+			if tokenString == "" {
+				http.Error(w, "missing bearer token", http.StatusUnauthorized)
+				return
+			}
+
+			claims := &Claims{}
+			_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+				kid, _ := t.Header["kid"].(string)
+				if key, ok := ks.key(kid); ok {
+					return key, nil
+				}
+				// Read-through: the issuer may have rotated since our last
+				// fetch, so refresh once before giving up on this kid.
+				if err := ks.refresh(r.Context()); err != nil {
+					return nil, fmt.Errorf("refreshing jwks: %w", err)
+				}
+				if key, ok := ks.key(kid); ok {
+					return key, nil
+				}
+				return nil, fmt.Errorf("unknown key id %q", kid)
+			},
+				jwt.WithValidMethods([]string{"RS256"}),
+				jwt.WithIssuer(cfg.Issuer),
+				jwt.WithAudience(cfg.Audience),
+			)
+			if err != nil {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), claimsContextKey, *claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// bearerToken extracts the raw JWT from the Authorization header or one of
+// cloudflared's carrier headers.
+func bearerToken(r *http.Request) string {
+	if v := r.Header.Get("Authorization"); strings.HasPrefix(v, "Bearer ") {
+		return strings.TrimPrefix(v, "Bearer ")
+	}
+	if v := r.Header.Get("Cf-Access-Jwt-Assertion"); v != "" {
+		return v
+	}
+	return r.Header.Get("Cf-Access-Token")
+}
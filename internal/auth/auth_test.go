@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func publicKeyToJWK(pub *rsa.PublicKey, kid string) jwk {
+	return jwk{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}
+}
+
+// jwksTestServer serves a mutable set of JWKs, so tests can simulate key
+// rotation mid-test.
+type jwksTestServer struct {
+	*httptest.Server
+	mu   sync.Mutex
+	keys []jwk
+}
+
+func newJWKSTestServer() *jwksTestServer {
+	s := &jwksTestServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(jwksDoc{Keys: s.keys})
+	}))
+	return s
+}
+
+func (s *jwksTestServer) setKeys(keys ...jwk) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return signed
+}
+
+func TestRequireJWT(t *testing.T) {
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	const kid1 = "key-1"
+
+	jwks := newJWKSTestServer()
+	defer jwks.Close()
+	jwks.setKeys(publicKeyToJWK(&key1.PublicKey, kid1))
+
+	cfg := Config{
+		Issuer:   "https://issuer.example",
+		Audience: "my-audience",
+		JWKSURL:  jwks.URL,
+		JWKSTTL:  time.Hour, // long enough that only the read-through path can pick up new keys
+	}
+
+	var gotClaims Claims
+	handler := RequireJWT(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotClaims, _ = ClaimsFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	now := time.Now()
+	baseClaims := func() jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Issuer:    cfg.Issuer,
+			Audience:  jwt.ClaimStrings{cfg.Audience},
+			Subject:   "user-1",
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		}
+	}
+
+	do := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		claims := Claims{RegisteredClaims: baseClaims(), Email: "user@example.com"}
+		rr := do(signToken(t, key1, kid1, claims))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rr.Code)
+		}
+		if gotClaims.Subject != "user-1" || gotClaims.Email != "user@example.com" {
+			t.Errorf("unexpected claims in context: %+v", gotClaims)
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		claims := baseClaims()
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Hour))
+		rr := do(signToken(t, key1, kid1, Claims{RegisteredClaims: claims}))
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want 401", rr.Code)
+		}
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		claims := baseClaims()
+		claims.Audience = jwt.ClaimStrings{"other-audience"}
+		rr := do(signToken(t, key1, kid1, Claims{RegisteredClaims: claims}))
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want 401", rr.Code)
+		}
+	})
+
+	t.Run("rotated kid triggers read-through refresh", func(t *testing.T) {
+		key2, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("generating key: %v", err)
+		}
+		const kid2 = "key-2"
+
+		// The JWKS endpoint now serves both keys, but our middleware's
+		// cache (fetched once at RequireJWT construction) only knows kid1.
+		jwks.setKeys(publicKeyToJWK(&key1.PublicKey, kid1), publicKeyToJWK(&key2.PublicKey, kid2))
+
+		claims := Claims{RegisteredClaims: baseClaims()}
+		rr := do(signToken(t, key2, kid2, claims))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 after read-through refresh", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"os"
+	"time"
+)
+
+// Config configures RequireJWT.
+type Config struct {
+	Issuer   string
+	Audience string
+	JWKSURL  string
+	JWKSTTL  time.Duration
+}
+
+// LoadConfig reads Config from the environment:
+//
+//	JWT_ISSUER     expected "iss" claim
+//	JWT_AUDIENCE   expected member of the "aud" claim
+//	JWT_JWKS_URL   JWKS endpoint to fetch signing keys from
+//	JWT_JWKS_TTL   refresh interval, default 10m, parsed with time.ParseDuration
+func LoadConfig() Config {
+	return Config{
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+		JWKSURL:  os.Getenv("JWT_JWKS_URL"),
+		JWKSTTL:  envDuration("JWT_JWKS_TTL", 10*time.Minute),
+	}
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,211 @@
+// Package cache wraps a pooled Redis client behind a small Cache interface:
+// plain get/set, a single-flight-protected GetOrLoad for cache-aside
+// workloads, pipelined MGET, and pub/sub subscriptions. A FakeCache (see
+// fake.go) implements the same interface in memory for tests.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound is returned by Get when a key has no value. GetOrLoad calls its
+// loader on a miss and returns the loader's result or error instead.
+var ErrNotFound = errors.New("cache: key not found")
+
+// Message is a single pub/sub message delivered to a Subscription.
+type Message struct {
+	Channel string
+	Payload string
+}
+
+// Subscription is a live pub/sub subscription to one channel.
+type Subscription interface {
+	// Messages returns the channel messages are delivered on. It is closed
+	// once the subscription is closed or the underlying connection gives up.
+	Messages() <-chan Message
+	Close() error
+}
+
+// Cache is the interface handlers depend on, satisfied by RedisCache in
+// production and FakeCache in tests.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// GetOrLoad returns the cached value for key, or calls loader on a miss.
+	// Concurrent misses for the same key are coalesced via single-flight so
+	// only one loader call reaches the origin; the result is cached for ttl.
+	GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error)) ([]byte, error)
+	// MGet fetches keys in a single pipelined round-trip. Missing keys are
+	// simply absent from the returned map.
+	MGet(ctx context.Context, keys []string) (map[string][]byte, error)
+	Subscribe(ctx context.Context, channel string) Subscription
+}
+
+// Config configures a RedisCache's connection pool.
+type Config struct {
+	Addr         string
+	Password     string
+	DB           int
+	PoolSize     int
+	MinIdleConns int
+	DialTimeout  time.Duration
+}
+
+// LoadConfig reads Config from the environment:
+//
+//	REDIS_ADDR          default "localhost:6379"
+//	REDIS_PASSWORD      default "" (no auth)
+//	REDIS_DB            default 0
+//	REDIS_POOL_SIZE     default 10
+//	REDIS_MIN_IDLE      default 0
+//	REDIS_DIAL_TIMEOUT  default 5s, parsed with time.ParseDuration
+func LoadConfig() Config {
+	return Config{
+		Addr:         envOr("REDIS_ADDR", "localhost:6379"),
+		Password:     os.Getenv("REDIS_PASSWORD"),
+		DB:           envInt("REDIS_DB", 0),
+		PoolSize:     envInt("REDIS_POOL_SIZE", 10),
+		MinIdleConns: envInt("REDIS_MIN_IDLE", 0),
+		DialTimeout:  envDuration("REDIS_DIAL_TIMEOUT", 5*time.Second),
+	}
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func envInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+// RedisCache is a Cache backed by a pooled *redis.Client.
+type RedisCache struct {
+	rdb *redis.Client
+	sf  singleflight.Group
+}
+
+// NewRedisCache dials Redis per cfg and pings it before returning.
+func NewRedisCache(cfg Config) (*RedisCache, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		MinIdleConns: cfg.MinIdleConns,
+		DialTimeout:  cfg.DialTimeout,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
+	defer cancel()
+	if _, err := rdb.Ping(ctx).Result(); err != nil {
+		return nil, fmt.Errorf("connecting to redis at %s: %w", cfg.Addr, err)
+	}
+	return &RedisCache{rdb: rdb}, nil
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	val, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	return val, err
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.rdb.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *RedisCache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error)) ([]byte, error) {
+	if val, err := c.Get(ctx, key); err == nil {
+		return val, nil
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	v, err, _ := c.sf.Do(key, func() (interface{}, error) {
+		data, err := loader()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Set(ctx, key, data, ttl); err != nil {
+			return nil, err
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+func (c *RedisCache) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	pipe := c.rdb.Pipeline()
+	cmds := make(map[string]*redis.StringCmd, len(keys))
+	for _, key := range keys {
+		cmds[key] = pipe.Get(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	result := make(map[string][]byte, len(keys))
+	for key, cmd := range cmds {
+		val, err := cmd.Bytes()
+		if err != nil {
+			continue // key missing, leave it out of the result
+		}
+		result[key] = val
+	}
+	return result, nil
+}
+
+func (c *RedisCache) Subscribe(ctx context.Context, channel string) Subscription {
+	ps := c.rdb.Subscribe(ctx, channel)
+	out := make(chan Message)
+	go func() {
+		defer close(out)
+		for msg := range ps.Channel() {
+			out <- Message{Channel: msg.Channel, Payload: msg.Payload}
+		}
+	}()
+	return &redisSubscription{ps: ps, messages: out}
+}
+
+type redisSubscription struct {
+	ps       *redis.PubSub
+	messages chan Message
+}
+
+func (s *redisSubscription) Messages() <-chan Message { return s.messages }
+func (s *redisSubscription) Close() error             { return s.ps.Close() }
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFakeCache_GetOrLoad_SingleflightDedup(t *testing.T) {
+	c := NewFakeCache()
+	var loads int32
+
+	loader := func() ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		time.Sleep(10 * time.Millisecond)
+		return []byte("value"), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]byte, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			val, err := c.GetOrLoad(context.Background(), "k", time.Minute, loader)
+			if err != nil {
+				t.Errorf("GetOrLoad: %v", err)
+				return
+			}
+			results[i] = val
+		}(i)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&loads); got != 1 {
+		t.Errorf("loader called %d times, want 1", got)
+	}
+	for i, r := range results {
+		if string(r) != "value" {
+			t.Errorf("result[%d] = %q, want %q", i, r, "value")
+		}
+	}
+}
+
+func TestFakeCache_GetOrLoad_CachesAcrossCalls(t *testing.T) {
+	c := NewFakeCache()
+	var loads int32
+	loader := func() ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		return []byte("value"), nil
+	}
+
+	ctx := context.Background()
+	if _, err := c.GetOrLoad(ctx, "k", time.Minute, loader); err != nil {
+		t.Fatalf("first GetOrLoad: %v", err)
+	}
+	if _, err := c.GetOrLoad(ctx, "k", time.Minute, loader); err != nil {
+		t.Fatalf("second GetOrLoad: %v", err)
+	}
+	if got := atomic.LoadInt32(&loads); got != 1 {
+		t.Errorf("loader called %d times across two calls, want 1", got)
+	}
+}
+
+func TestFakeCache_MGet(t *testing.T) {
+	c := NewFakeCache()
+	ctx := context.Background()
+	_ = c.Set(ctx, "a", []byte("1"), 0)
+	_ = c.Set(ctx, "b", []byte("2"), 0)
+
+	got, err := c.MGet(ctx, []string{"a", "b", "missing"})
+	if err != nil {
+		t.Fatalf("MGet: %v", err)
+	}
+	if string(got["a"]) != "1" || string(got["b"]) != "2" {
+		t.Errorf("unexpected MGet result: %+v", got)
+	}
+	if _, ok := got["missing"]; ok {
+		t.Errorf("expected missing key to be absent, got %v", got["missing"])
+	}
+}
+
+func TestFakeCache_PubSub(t *testing.T) {
+	c := NewFakeCache()
+	sub := c.Subscribe(context.Background(), "events")
+	defer sub.Close()
+
+	c.Publish("events", "hello")
+
+	select {
+	case msg := <-sub.Messages():
+		if msg.Payload != "hello" {
+			t.Errorf("payload = %q, want %q", msg.Payload, "hello")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+}
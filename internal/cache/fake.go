@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// FakeCache is an in-memory Cache for tests. It implements single-flight
+// deduplication the same way RedisCache does, but has no TTL expiry and no
+// network calls.
+type FakeCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	subs map[string][]chan Message
+	sf   singleflight.Group
+}
+
+// NewFakeCache returns an empty FakeCache.
+func NewFakeCache() *FakeCache {
+	return &FakeCache{
+		data: make(map[string][]byte),
+		subs: make(map[string][]chan Message),
+	}
+}
+
+func (f *FakeCache) Get(ctx context.Context, key string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	val, ok := f.data[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return val, nil
+}
+
+func (f *FakeCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = value
+	return nil
+}
+
+func (f *FakeCache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error)) ([]byte, error) {
+	if val, err := f.Get(ctx, key); err == nil {
+		return val, nil
+	}
+
+	v, err, _ := f.sf.Do(key, func() (interface{}, error) {
+		data, err := loader()
+		if err != nil {
+			return nil, err
+		}
+		_ = f.Set(ctx, key, data, ttl)
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+func (f *FakeCache) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if val, ok := f.data[key]; ok {
+			result[key] = val
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeCache) Subscribe(ctx context.Context, channel string) Subscription {
+	ch := make(chan Message, 1)
+	f.mu.Lock()
+	f.subs[channel] = append(f.subs[channel], ch)
+	f.mu.Unlock()
+	return &fakeSubscription{cache: f, channel: channel, messages: ch}
+}
+
+// Publish delivers payload to every active subscription on channel. It is a
+// test helper, not part of the Cache interface.
+func (f *FakeCache) Publish(channel, payload string) {
+	f.mu.Lock()
+	subs := append([]chan Message(nil), f.subs[channel]...)
+	f.mu.Unlock()
+	for _, ch := range subs {
+		ch <- Message{Channel: channel, Payload: payload}
+	}
+}
+
+type fakeSubscription struct {
+	cache    *FakeCache
+	channel  string
+	messages chan Message
+}
+
+func (s *fakeSubscription) Messages() <-chan Message { return s.messages }
+
+func (s *fakeSubscription) Close() error {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+	subs := s.cache.subs[s.channel]
+	for i, ch := range subs {
+		if ch == s.messages {
+			s.cache.subs[s.channel] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	close(s.messages)
+	return nil
+}
@@ -0,0 +1,176 @@
+// Package reqlog is a structured, per-request JSON logging middleware. Each
+// request gets an X-Request-ID (accepted from the incoming request, else
+// generated), a single JSON log line, and an entry in an in-memory ring
+// buffer for /debug/requests.
+package reqlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Entry is one structured access log line / ring buffer record.
+type Entry struct {
+	Ts         string `json:"ts"`
+	Level      string `json:"level"`
+	ReqID      string `json:"req_id"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Status     int    `json:"status"`
+	Bytes      int    `json:"bytes"`
+	DurationMS int64  `json:"duration_ms"`
+	RemoteIP   string `json:"remote_ip"`
+	UA         string `json:"ua"`
+	Referrer   string `json:"referrer"`
+	Error      string `json:"error,omitempty"`
+}
+
+// requestIDHeader is both accepted on incoming requests and echoed on
+// responses.
+const requestIDHeader = "X-Request-ID"
+
+type ctxKey int
+
+const (
+	reqIDKey ctxKey = iota
+	errHolderKey
+)
+
+type errHolder struct {
+	mu  sync.Mutex
+	msg string
+}
+
+// RequestIDFromContext returns the request ID assigned by Middleware, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(reqIDKey).(string)
+	return id
+}
+
+// SetError records err on the current request's log entry. Handlers use this
+// in place of ad-hoc log.Printf calls; the error surfaces in the request's
+// single structured log line instead of a separate unstructured one.
+func SetError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	if h, ok := ctx.Value(errHolderKey).(*errHolder); ok {
+		h.mu.Lock()
+		h.msg = err.Error()
+		h.mu.Unlock()
+	}
+}
+
+// noAccessLogPaths are recorded in the ring buffer but not written to the
+// access log, so routine health checks don't drown out real traffic.
+var noAccessLogPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// Middleware logs one JSON line per request to out and records every request
+// (including health checks) in ring.
+func Middleware(out io.Writer, ring *Ring) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			reqID := r.Header.Get(requestIDHeader)
+			if reqID == "" {
+				reqID = ulid.Make().String()
+			}
+			w.Header().Set(requestIDHeader, reqID)
+
+			ctx := context.WithValue(r.Context(), reqIDKey, reqID)
+			ctx = context.WithValue(ctx, errHolderKey, &errHolder{})
+			r = r.WithContext(ctx)
+
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rw, r)
+
+			entry := Entry{
+				Ts:         start.UTC().Format(time.RFC3339Nano),
+				Level:      "info",
+				ReqID:      reqID,
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				Status:     rw.status,
+				Bytes:      rw.bytes,
+				DurationMS: time.Since(start).Milliseconds(),
+				RemoteIP:   remoteIP(r),
+				UA:         r.UserAgent(),
+				Referrer:   r.Referer(),
+				Error:      errorFromContext(r.Context()),
+			}
+			if ring != nil {
+				ring.Add(entry)
+			}
+			if noAccessLogPaths[r.URL.Path] {
+				return
+			}
+			if data, err := json.Marshal(entry); err == nil {
+				fmt.Fprintln(out, string(data))
+			}
+		})
+	}
+}
+
+func errorFromContext(ctx context.Context) string {
+	h, ok := ctx.Value(errHolderKey).(*errHolder)
+	if !ok {
+		return ""
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.msg
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// responseWriter captures the status code and byte count written through it.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.status = status
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += n
+	return n, err
+}
+
+// Flush lets responseWriter pass through to a streaming handler such as
+// pubsubHandler, which needs http.Flusher on the ResponseWriter it's given.
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,126 @@
+package reqlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware_JSONSchemaAndRequestID(t *testing.T) {
+	var out bytes.Buffer
+	ring := NewRing(10)
+
+	handler := Middleware(&out, ring)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if RequestIDFromContext(r.Context()) == "" {
+			t.Error("expected non-empty request ID in context")
+		}
+		SetError(r.Context(), errors.New("boom"))
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/info?x=1", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	req.Header.Set("Referer", "https://example.com")
+	req.RemoteAddr = "9.9.9.9:1234"
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	respReqID := rr.Header().Get("X-Request-ID")
+	if respReqID == "" {
+		t.Fatal("expected X-Request-ID on response")
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(bytes.TrimSpace(out.Bytes()), &entry); err != nil {
+		t.Fatalf("logged line is not valid JSON: %v (line: %q)", err, out.String())
+	}
+
+	if entry.ReqID != respReqID {
+		t.Errorf("logged req_id %q != response header %q", entry.ReqID, respReqID)
+	}
+	if entry.Method != http.MethodGet {
+		t.Errorf("method = %q, want GET", entry.Method)
+	}
+	if entry.Path != "/info" {
+		t.Errorf("path = %q, want /info", entry.Path)
+	}
+	if entry.Status != http.StatusTeapot {
+		t.Errorf("status = %d, want %d", entry.Status, http.StatusTeapot)
+	}
+	if entry.Bytes != len("hello") {
+		t.Errorf("bytes = %d, want %d", entry.Bytes, len("hello"))
+	}
+	if entry.RemoteIP != "9.9.9.9" {
+		t.Errorf("remote_ip = %q, want 9.9.9.9", entry.RemoteIP)
+	}
+	if entry.UA != "test-agent" {
+		t.Errorf("ua = %q, want test-agent", entry.UA)
+	}
+	if entry.Referrer != "https://example.com" {
+		t.Errorf("referrer = %q, want https://example.com", entry.Referrer)
+	}
+	if entry.Error != "boom" {
+		t.Errorf("error = %q, want boom", entry.Error)
+	}
+}
+
+func TestMiddleware_PropagatesIncomingRequestID(t *testing.T) {
+	var out bytes.Buffer
+	ring := NewRing(10)
+
+	handler := Middleware(&out, ring)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "client-supplied-id")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if got := rr.Header().Get("X-Request-ID"); got != "client-supplied-id" {
+		t.Errorf("X-Request-ID = %q, want echoed client-supplied-id", got)
+	}
+}
+
+func TestMiddleware_HealthChecksSkipAccessLogButFillRing(t *testing.T) {
+	var out bytes.Buffer
+	ring := NewRing(10)
+
+	handler := Middleware(&out, ring)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if out.Len() != 0 {
+		t.Errorf("expected no access log output for /healthz, got %q", out.String())
+	}
+	entries := ring.Entries()
+	if len(entries) != 1 || entries[0].Path != "/healthz" {
+		t.Errorf("expected /healthz to be recorded in the ring, got %+v", entries)
+	}
+}
+
+func TestRing_FIFOEviction(t *testing.T) {
+	ring := NewRing(3)
+	for i := 0; i < 5; i++ {
+		ring.Add(Entry{Path: string(rune('a' + i))})
+	}
+
+	entries := ring.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("len(entries) = %d, want 3", len(entries))
+	}
+	want := []string{"c", "d", "e"}
+	for i, e := range entries {
+		if e.Path != want[i] {
+			t.Errorf("entries[%d].Path = %q, want %q", i, e.Path, want[i])
+		}
+	}
+}
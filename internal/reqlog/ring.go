@@ -0,0 +1,36 @@
+package reqlog
+
+import "sync"
+
+// Ring is a fixed-capacity FIFO buffer of Entry, used to back /debug/requests
+// so ops can inspect recent traffic (including health checks) without
+// grepping the access log.
+type Ring struct {
+	mu       sync.Mutex
+	entries  []Entry
+	capacity int
+}
+
+// NewRing returns a Ring holding up to capacity entries.
+func NewRing(capacity int) *Ring {
+	return &Ring{capacity: capacity}
+}
+
+// Add appends e, evicting the oldest entry first if the ring is full.
+func (r *Ring) Add(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+	if over := len(r.entries) - r.capacity; over > 0 {
+		r.entries = r.entries[over:]
+	}
+}
+
+// Entries returns a snapshot of the buffered entries, oldest first.
+func (r *Ring) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Entry, len(r.entries))
+	copy(out, r.entries)
+	return out
+}
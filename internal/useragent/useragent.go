@@ -0,0 +1,124 @@
+// Package useragent parses User-Agent strings into a structured Result:
+// browser, rendering engine, OS, device type, and bot detection. It wraps
+// github.com/mssola/user_agent and adds an LRU cache in front of it, since
+// real traffic repeats the same handful of User-Agent strings heavily.
+package useragent
+
+import (
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/mssola/user_agent"
+)
+
+// Device classifies the kind of device a User-Agent was sent from.
+type Device string
+
+const (
+	DeviceDesktop Device = "desktop"
+	DeviceMobile  Device = "mobile"
+	DeviceTablet  Device = "tablet"
+	DeviceTV      Device = "tv"
+)
+
+// Result is the structured outcome of parsing a User-Agent string.
+type Result struct {
+	BrowserName    string `json:"browser_name"`
+	BrowserVersion string `json:"browser_version"`
+	EngineName     string `json:"engine_name"`
+	EngineVersion  string `json:"engine_version"`
+	OSName         string `json:"os_name"`
+	OSVersion      string `json:"os_version"`
+	Device         Device `json:"device"`
+	Mobile         bool   `json:"mobile"`
+	Bot            bool   `json:"bot"`
+	BotName        string `json:"bot_name,omitempty"`
+	UserAgent      string `json:"user_agent"`
+}
+
+// defaultCacheSize bounds the default parser's LRU; real deployments see a
+// long tail of distinct bots but a small set of popular browser UAs.
+const defaultCacheSize = 4096
+
+var defaultParser = NewParser(defaultCacheSize)
+
+// Parse parses ua using the package's default, cache-backed parser.
+func Parse(ua string) Result {
+	return defaultParser.Parse(ua)
+}
+
+// Parser parses User-Agent strings and caches the results.
+type Parser struct {
+	cache *lru.Cache[string, Result]
+}
+
+// NewParser returns a Parser backed by an LRU cache holding up to size
+// entries. A non-positive size disables caching.
+func NewParser(size int) *Parser {
+	if size <= 0 {
+		return &Parser{}
+	}
+	cache, err := lru.New[string, Result](size)
+	if err != nil {
+		// lru.New only errors for size <= 0, already excluded above.
+		return &Parser{}
+	}
+	return &Parser{cache: cache}
+}
+
+// Parse returns the parsed Result for ua, serving from cache when possible.
+func (p *Parser) Parse(ua string) Result {
+	if p.cache != nil {
+		if res, ok := p.cache.Get(ua); ok {
+			return res
+		}
+	}
+	res := parse(ua)
+	if p.cache != nil {
+		p.cache.Add(ua, res)
+	}
+	return res
+}
+
+func parse(ua string) Result {
+	uaStr := user_agent.New(ua)
+
+	browserName, browserVersion := uaStr.Browser()
+	engineName, engineVersion := uaStr.Engine()
+	osInfo := uaStr.OSInfo()
+
+	res := Result{
+		BrowserName:    browserName,
+		BrowserVersion: browserVersion,
+		EngineName:     engineName,
+		EngineVersion:  engineVersion,
+		OSName:         osInfo.Name,
+		OSVersion:      osInfo.Version,
+		Mobile:         uaStr.Mobile(),
+		Bot:            uaStr.Bot(),
+		UserAgent:      ua,
+	}
+	if res.Bot {
+		// mssola/user_agent reports the bot's name via Browser() when Bot() is true.
+		res.BotName = browserName
+	}
+	res.Device = classifyDevice(ua, res)
+	return res
+}
+
+var tvMarkers = []string{"SmartTV", "GoogleTV", "AppleTV", "Tizen", "HbbTV", "NetCast", "Roku"}
+
+func classifyDevice(ua string, res Result) Device {
+	for _, marker := range tvMarkers {
+		if strings.Contains(ua, marker) {
+			return DeviceTV
+		}
+	}
+	if strings.Contains(ua, "iPad") || (strings.Contains(ua, "Android") && !strings.Contains(ua, "Mobile")) {
+		return DeviceTablet
+	}
+	if res.Mobile {
+		return DeviceMobile
+	}
+	return DeviceDesktop
+}
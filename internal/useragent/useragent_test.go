@@ -0,0 +1,93 @@
+package useragent
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		ua          string
+		wantBrowser string
+		wantOS      string
+		wantDevice  Device
+		wantBot     bool
+		wantBotName string
+	}{
+		{
+			name:        "Chrome on Android",
+			ua:          "Mozilla/5.0 (Linux; Android 11; Pixel 5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.120 Mobile Safari/537.36",
+			wantBrowser: "Chrome",
+			wantOS:      "Android",
+			wantDevice:  DeviceMobile,
+		},
+		{
+			name:        "Safari on iPad",
+			ua:          "Mozilla/5.0 (iPad; CPU OS 14_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0 Mobile/15E148 Safari/604.1",
+			wantBrowser: "Safari",
+			wantDevice:  DeviceTablet,
+		},
+		{
+			name:        "Edge Chromium",
+			ua:          "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36 Edg/91.0.864.59",
+			wantBrowser: "Edge",
+			wantOS:      "Windows",
+			wantDevice:  DeviceDesktop,
+		},
+		{
+			name:        "Googlebot",
+			ua:          "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			wantBrowser: "Googlebot",
+			wantBot:     true,
+			wantBotName: "Googlebot",
+			wantDevice:  DeviceDesktop,
+		},
+		{
+			name:        "curl",
+			ua:          "curl/7.64.1",
+			wantBrowser: "curl",
+			wantDevice:  DeviceDesktop,
+		},
+		{
+			name:       "empty user agent",
+			ua:         "",
+			wantDevice: DeviceDesktop,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Parse(tt.ua)
+			if res.BrowserName != tt.wantBrowser {
+				t.Errorf("BrowserName = %q, want %q", res.BrowserName, tt.wantBrowser)
+			}
+			if tt.wantOS != "" && res.OSName != tt.wantOS {
+				t.Errorf("OSName = %q, want %q", res.OSName, tt.wantOS)
+			}
+			if res.Device != tt.wantDevice {
+				t.Errorf("Device = %q, want %q", res.Device, tt.wantDevice)
+			}
+			if res.Bot != tt.wantBot {
+				t.Errorf("Bot = %v, want %v", res.Bot, tt.wantBot)
+			}
+			if res.BotName != tt.wantBotName {
+				t.Errorf("BotName = %q, want %q", res.BotName, tt.wantBotName)
+			}
+			if res.UserAgent != tt.ua {
+				t.Errorf("UserAgent = %q, want %q", res.UserAgent, tt.ua)
+			}
+		})
+	}
+}
+
+func TestParser_Caching(t *testing.T) {
+	p := NewParser(2)
+	ua := "curl/7.64.1"
+
+	first := p.Parse(ua)
+	second := p.Parse(ua)
+	if first != second {
+		t.Errorf("expected cached result to be identical: %+v vs %+v", first, second)
+	}
+	if _, ok := p.cache.Get(ua); !ok {
+		t.Errorf("expected ua to be cached")
+	}
+}
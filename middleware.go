@@ -0,0 +1,86 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+)
+
+// MiddlewareConfig controls the optional middleware chain wrapped around the
+// router. Zero values disable the corresponding middleware where it makes
+// sense (e.g. an empty CanonicalHost skips the CanonicalHost redirect).
+type MiddlewareConfig struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	CanonicalHost  string
+	CanonicalCode  int
+}
+
+// loadMiddlewareConfig reads MiddlewareConfig from the environment:
+//
+//	CORS_ALLOWED_ORIGINS  comma separated list, default "*"
+//	CORS_ALLOWED_METHODS  comma separated list, default "GET,POST"
+//	CORS_ALLOWED_HEADERS  comma separated list, default "Content-Type"
+//	CANONICAL_HOST        e.g. "example.com", empty disables the redirect
+//	CANONICAL_REDIRECT_CODE  defaults to http.StatusMovedPermanently
+func loadMiddlewareConfig() MiddlewareConfig {
+	cfg := MiddlewareConfig{
+		AllowedOrigins: splitEnvList("CORS_ALLOWED_ORIGINS", "*"),
+		AllowedMethods: splitEnvList("CORS_ALLOWED_METHODS", "GET,POST"),
+		AllowedHeaders: splitEnvList("CORS_ALLOWED_HEADERS", "Content-Type"),
+		CanonicalHost:  os.Getenv("CANONICAL_HOST"),
+		CanonicalCode:  http.StatusMovedPermanently,
+	}
+	if code := os.Getenv("CANONICAL_REDIRECT_CODE"); code != "" {
+		if n, err := strconv.Atoi(code); err == nil {
+			cfg.CanonicalCode = n
+		}
+	}
+	return cfg
+}
+
+func splitEnvList(key, def string) []string {
+	val := os.Getenv(key)
+	if val == "" {
+		val = def
+	}
+	parts := strings.Split(val, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// buildMiddleware assembles the gorilla/handlers middleware chain in the
+// order it should run: panic recovery first so it can catch everything
+// downstream, then the canonical host redirect, CORS, and gzip/deflate
+// compression last so it only compresses what actually gets written.
+func buildMiddleware(cfg MiddlewareConfig) []mux.MiddlewareFunc {
+	mw := []mux.MiddlewareFunc{
+		handlers.RecoveryHandler(
+			handlers.RecoveryLogger(log.New(log.Writer(), "", log.LstdFlags)),
+			handlers.PrintRecoveryStack(true),
+		),
+	}
+	if cfg.CanonicalHost != "" {
+		mw = append(mw, handlers.CanonicalHost(cfg.CanonicalHost, cfg.CanonicalCode))
+	}
+	mw = append(mw, handlers.CORS(
+		handlers.AllowedOrigins(cfg.AllowedOrigins),
+		handlers.AllowedMethods(cfg.AllowedMethods),
+		handlers.AllowedHeaders(cfg.AllowedHeaders),
+	))
+	mw = append(mw, func(h http.Handler) http.Handler {
+		return handlers.CompressHandler(h)
+	})
+	return mw
+}
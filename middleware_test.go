@@ -0,0 +1,122 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func newTestRouter(cfg MiddlewareConfig) *mux.Router {
+	r := mux.NewRouter()
+	r.HandleFunc("/info", infoHandler)
+	r.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	for _, mw := range buildMiddleware(cfg) {
+		r.Use(mw)
+	}
+	return r
+}
+
+func TestBuildMiddleware_CORSPreflight(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            MiddlewareConfig
+		origin         string
+		wantStatusCode int
+		wantAllowOrig  string
+	}{
+		{
+			name:           "allowed origin",
+			cfg:            MiddlewareConfig{AllowedOrigins: []string{"https://example.com"}, AllowedMethods: []string{"GET"}, AllowedHeaders: []string{"Content-Type"}},
+			origin:         "https://example.com",
+			wantStatusCode: http.StatusOK,
+			wantAllowOrig:  "https://example.com",
+		},
+		{
+			name:           "wildcard origin",
+			cfg:            MiddlewareConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, AllowedHeaders: []string{"Content-Type"}},
+			origin:         "https://anything.test",
+			wantStatusCode: http.StatusOK,
+			wantAllowOrig:  "*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRouter(tt.cfg)
+
+			req := httptest.NewRequest(http.MethodOptions, "/info", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", "GET")
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatusCode {
+				t.Errorf("status = %d, want %d", rr.Code, tt.wantStatusCode)
+			}
+			if got := rr.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowOrig {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllowOrig)
+			}
+		})
+	}
+}
+
+func TestBuildMiddleware_CompressesInfoResponse(t *testing.T) {
+	cfg := MiddlewareConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, AllowedHeaders: []string{"Content-Type"}}
+	r := newTestRouter(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/info", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+	if got := rr.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+
+	gz, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	defer gz.Close()
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("reading gzip body: %v", err)
+	}
+	if !strings.Contains(string(body), "client_ip") {
+		t.Errorf("decompressed body missing expected field: %s", body)
+	}
+}
+
+func TestBuildMiddleware_RecoversPanic(t *testing.T) {
+	var logBuf strings.Builder
+	log.SetOutput(&logBuf)
+	defer log.SetOutput(os.Stderr)
+
+	cfg := MiddlewareConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, AllowedHeaders: []string{"Content-Type"}}
+	r := newTestRouter(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rr.Code)
+	}
+	if !strings.Contains(logBuf.String(), "boom") {
+		t.Errorf("expected recovered panic to be logged, got: %s", logBuf.String())
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// loadTrustedProxies parses TRUSTED_PROXIES, a comma separated list of CIDRs
+// (a bare IP is treated as a /32 or /128), into the CIDR set ProxyHeaders
+// trusts to supply forwarding headers. An empty/unset env var yields no
+// trusted proxies, so ProxyHeaders becomes a no-op.
+func loadTrustedProxies() []net.IPNet {
+	raw := os.Getenv("TRUSTED_PROXIES")
+	if raw == "" {
+		return nil
+	}
+
+	var cidrs []net.IPNet
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if strings.Contains(entry, ":") {
+				entry += "/128"
+			} else {
+				entry += "/32"
+			}
+		}
+		_, ipnet, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Printf("TRUSTED_PROXIES: ignoring invalid entry %q: %v", entry, err)
+			continue
+		}
+		cidrs = append(cidrs, *ipnet)
+	}
+	return cidrs
+}
+
+// ProxyHeaders returns a middleware that rewrites r.RemoteAddr from the
+// Forwarded (RFC 7239) or X-Forwarded-For header, but only when the direct
+// peer (r.RemoteAddr before rewriting) falls within trustedCIDRs. Untrusted
+// peers are passed through unmodified, so a spoofed X-Forwarded-For from the
+// public internet is ignored.
+func ProxyHeaders(trustedCIDRs []net.IPNet) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				peer = r.RemoteAddr
+			}
+			if !cidrsContain(trustedCIDRs, peer) {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			if fwd := r.Header.Get("Forwarded"); fwd != "" {
+				if ip := leftmostUntrustedForwarded(fwd, trustedCIDRs); ip != "" {
+					r.RemoteAddr = ip
+				}
+			} else if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+				r.RemoteAddr = leftmostUntrustedXFF(xff, trustedCIDRs)
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+func cidrsContain(cidrs []net.IPNet, ipStr string) bool {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range cidrs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// leftmostUntrustedXFF walks an X-Forwarded-For chain from right (closest
+// hop) to left, skipping entries that are themselves trusted proxies, and
+// returns the first hop it meets that isn't trusted: the right-most untrusted
+// entry, just left of the trailing run of trusted proxies. Entries further
+// left are not consulted. If every hop is trusted, the left-most is returned.
+func leftmostUntrustedXFF(xff string, trustedCIDRs []net.IPNet) string {
+	hops := strings.Split(xff, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if !cidrsContain(trustedCIDRs, hop) {
+			return hop
+		}
+	}
+	return strings.TrimSpace(hops[0])
+}
+
+// leftmostUntrustedForwarded applies the same hop-selection rule as
+// leftmostUntrustedXFF to the "for" parameters of an RFC 7239 Forwarded
+// header, which may chain multiple forwarded-pairs separated by commas.
+func leftmostUntrustedForwarded(header string, trustedCIDRs []net.IPNet) string {
+	var hops []string
+	for _, elem := range strings.Split(header, ",") {
+		if ip := forwardedFor(elem); ip != "" {
+			hops = append(hops, ip)
+		}
+	}
+	if len(hops) == 0 {
+		return ""
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !cidrsContain(trustedCIDRs, hops[i]) {
+			return hops[i]
+		}
+	}
+	return hops[0]
+}
+
+// forwardedFor extracts the "for" parameter's IP (port and brackets
+// stripped) from a single RFC 7239 forwarded-pair such as
+// `for="[2001:db8::1]:4711";proto=https;host=example.com`.
+func forwardedFor(pair string) string {
+	for _, kv := range strings.Split(pair, ";") {
+		kv = strings.TrimSpace(kv)
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(k), "for") {
+			continue
+		}
+		v = strings.Trim(strings.TrimSpace(v), `"`)
+		if host, _, err := net.SplitHostPort(v); err == nil {
+			return host
+		}
+		return strings.Trim(v, "[]")
+	}
+	return ""
+}
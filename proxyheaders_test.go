@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("net.ParseCIDR(%q): %v", s, err)
+	}
+	return *n
+}
+
+func TestProxyHeaders(t *testing.T) {
+	trusted := []net.IPNet{mustCIDR(t, "10.0.0.0/8")}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		wantIP     string
+	}{
+		{
+			name:       "untrusted peer with spoofed XFF is ignored",
+			remoteAddr: "203.0.113.5:1234",
+			headers:    map[string]string{"X-Forwarded-For": "6.6.6.6"},
+			wantIP:     "203.0.113.5",
+		},
+		{
+			name:       "trusted peer with chained XFF takes left-most non-trusted hop",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 10.0.0.9, 10.0.0.1"},
+			wantIP:     "1.2.3.4",
+		},
+		{
+			name:       "trusted peer with RFC 7239 Forwarded header, quoted IPv6",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"Forwarded": `for="[2001:db8::1]:4711";proto=https;host=example.com`},
+			wantIP:     "2001:db8::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIP string
+			inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotIP = getClientIP(r)
+			})
+			mw := ProxyHeaders(trusted)(inner)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			mw.ServeHTTP(httptest.NewRecorder(), req)
+
+			if gotIP != tt.wantIP {
+				t.Errorf("got client IP %q, want %q", gotIP, tt.wantIP)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/callicoder/go-docker/internal/cache"
+	"github.com/callicoder/go-docker/internal/reqlog"
+)
+
+// seedCache adds some predetermined data to the cache.
+func seedCache(ctx context.Context, c cache.Cache) {
+	log.Println("Seeding cache with initial data...")
+	if err := c.Set(ctx, "app:name", []byte("go-hello-server"), 0); err != nil {
+		log.Printf("Failed to seed data 'app:name': %v", err)
+	}
+	if err := c.Set(ctx, "user:1:name", []byte("Cetriolo"), 0); err != nil {
+		log.Printf("Failed to seed data 'user:1:name': %v", err)
+	}
+}
+
+// redisHandler retrieves a value from the cache by key.
+// Example: /redis?key=app:name
+func redisHandler(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		http.Error(w, "Query parameter 'key' is required", http.StatusBadRequest)
+		return
+	}
+
+	val, err := appCache.Get(r.Context(), key)
+	if errors.Is(err, cache.ErrNotFound) {
+		http.Error(w, fmt.Sprintf("Key '%s' not found", key), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Failed to retrieve data from cache", http.StatusInternalServerError)
+		reqlog.SetError(r.Context(), fmt.Errorf("cache GET error for key '%s': %w", key, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, string(val))
+}
+
+// mgetHandler fetches multiple keys in a single pipelined round-trip.
+// Example: /redis/mget?keys=app:name,user:1:name
+func mgetHandler(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("keys")
+	if raw == "" {
+		http.Error(w, "Query parameter 'keys' is required", http.StatusBadRequest)
+		return
+	}
+
+	parts := strings.Split(raw, ",")
+	keys := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			keys = append(keys, p)
+		}
+	}
+
+	values, err := appCache.MGet(r.Context(), keys)
+	if err != nil {
+		http.Error(w, "Failed to retrieve data from cache", http.StatusInternalServerError)
+		reqlog.SetError(r.Context(), fmt.Errorf("cache MGET error for keys %v: %w", keys, err))
+		return
+	}
+
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		out[k] = string(v)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// pubsubPingInterval is how often pubsubHandler sends a keepalive comment
+// while waiting for messages.
+const pubsubPingInterval = 15 * time.Second
+
+// writeSSEData writes payload as one or more SSE "data:" lines followed by
+// the blank line that terminates the event. Per the SSE spec, a multi-line
+// payload needs its own "data: " prefix on each line; writing it as a single
+// "data: %s\n\n" would let an embedded newline (e.g. pretty-printed JSON)
+// break the framing.
+func writeSSEData(w io.Writer, payload string) {
+	for _, line := range strings.Split(payload, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// pubsubHandler streams messages published to channel as Server-Sent Events
+// until the client disconnects.
+// Example: /pubsub?channel=notifications
+func pubsubHandler(w http.ResponseWriter, r *http.Request) {
+	channel := r.URL.Query().Get("channel")
+	if channel == "" {
+		http.Error(w, "Query parameter 'channel' is required", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	sub := appCache.Subscribe(r.Context(), channel)
+	defer sub.Close()
+
+	ticker := time.NewTicker(pubsubPingInterval)
+	defer ticker.Stop()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				return
+			}
+			writeSSEData(w, msg.Payload)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/callicoder/go-docker/internal/cache"
+)
+
+func TestWriteSSEData_MultilinePayloadGetsOnePrefixPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	writeSSEData(&buf, "line1\nline2")
+
+	want := "data: line1\ndata: line2\n\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRedisHandler(t *testing.T) {
+	fc := cache.NewFakeCache()
+	appCache = fc
+	_ = fc.Set(context.Background(), "app:name", []byte("go-hello-server"), 0)
+
+	req := httptest.NewRequest("GET", "/redis?key=app:name", nil)
+	rr := httptest.NewRecorder()
+	redisHandler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+	if rr.Body.String() != "go-hello-server\n" {
+		t.Errorf("body = %q, want %q", rr.Body.String(), "go-hello-server\n")
+	}
+
+	// Missing key -> 404
+	req2 := httptest.NewRequest("GET", "/redis?key=nope", nil)
+	rr2 := httptest.NewRecorder()
+	redisHandler(rr2, req2)
+	if rr2.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rr2.Code)
+	}
+}
+
+func TestMgetHandler_Ordering(t *testing.T) {
+	fc := cache.NewFakeCache()
+	appCache = fc
+	ctx := context.Background()
+	_ = fc.Set(ctx, "a", []byte("1"), 0)
+	_ = fc.Set(ctx, "b", []byte("2"), 0)
+	_ = fc.Set(ctx, "c", []byte("3"), 0)
+
+	req := httptest.NewRequest("GET", "/redis/mget?keys=c,a,b,missing", nil)
+	rr := httptest.NewRecorder()
+	mgetHandler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+
+	var got map[string]string
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := map[string]string{"a": "1", "b": "2", "c": "3"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v keys, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("key %q = %q, want %q", k, got[k], v)
+		}
+	}
+	if _, ok := got["missing"]; ok {
+		t.Errorf("expected missing key to be absent from response")
+	}
+}
+
+func TestPubsubHandler_SSEFraming(t *testing.T) {
+	fc := cache.NewFakeCache()
+	appCache = fc
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	req := httptest.NewRequest("GET", "/pubsub?channel=events", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		pubsubHandler(rr, req)
+		close(done)
+	}()
+
+	// Give the handler time to subscribe before publishing.
+	time.Sleep(20 * time.Millisecond)
+	fc.Publish("events", "hello")
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("pubsubHandler did not return after context cancellation")
+	}
+
+	body := rr.Body.String()
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	found := false
+	for _, l := range lines {
+		if l == "data: hello" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a %q line in SSE body, got: %q", "data: hello", body)
+	}
+}
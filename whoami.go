@@ -0,0 +1,25 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/callicoder/go-docker/internal/auth"
+)
+
+// whoamiHandler returns the subject/email claims of the JWT validated by
+// auth.RequireJWT. It must only be reached behind that middleware.
+func whoamiHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := auth.ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "missing claims", http.StatusInternalServerError)
+		return
+	}
+
+	resp := map[string]string{
+		"subject": claims.Subject,
+		"email":   claims.Email,
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(resp)
+}
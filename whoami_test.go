@@ -0,0 +1,98 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/callicoder/go-docker/internal/auth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwk mirrors the subset of RFC 7517 fields the auth package's JWKS client
+// understands; kept local to this test so it doesn't need an exported type.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func TestWhoamiHandler(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	const kid = "key-1"
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string][]jwk{
+			"keys": {{
+				Kty: "RSA",
+				Kid: kid,
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer jwksServer.Close()
+
+	cfg := auth.Config{
+		Issuer:   "https://issuer.example",
+		Audience: "go-docker",
+		JWKSURL:  jwksServer.URL,
+		JWKSTTL:  time.Hour,
+	}
+	handler := auth.RequireJWT(cfg)(http.HandlerFunc(whoamiHandler))
+
+	now := time.Now()
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    cfg.Issuer,
+			Audience:  jwt.ClaimStrings{cfg.Audience},
+			Subject:   "user-1",
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Email: "user@example.com",
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200, body: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp map[string]string
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp["subject"] != "user-1" || resp["email"] != "user@example.com" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestWhoamiHandler_MissingClaims(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	rr := httptest.NewRecorder()
+	whoamiHandler(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rr.Code)
+	}
+}